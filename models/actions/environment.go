@@ -0,0 +1,134 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// ActionEnvironment is a named deployment environment that a repository or its owning
+// organization has declared. workflow_dispatch inputs of type `environment` are
+// validated against this table.
+type ActionEnvironment struct {
+	ID      int64  `xorm:"pk autoincr"`
+	OwnerID int64  `xorm:"UNIQUE(s) index"` // the org (or user) the environment belongs to
+	RepoID  int64  `xorm:"UNIQUE(s) index"` // 0 when the environment is org-wide
+	Name    string `xorm:"UNIQUE(s) NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionEnvironment))
+}
+
+// EnvironmentNames returns the environment names visible to repoID, including any
+// declared on its owning organization.
+func EnvironmentNames(ctx context.Context, ownerID, repoID int64) ([]string, error) {
+	envs, err := FindEnvironments(ctx, ownerID, repoID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(envs))
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	return names, nil
+}
+
+// FindEnvironments returns the environments visible to repoID: those declared directly
+// on it plus any declared org-wide (RepoID == 0) on ownerID.
+func FindEnvironments(ctx context.Context, ownerID, repoID int64) ([]*ActionEnvironment, error) {
+	var envs []*ActionEnvironment
+	if err := db.GetEngine(ctx).
+		Where("owner_id = ? AND (repo_id = ? OR repo_id = 0)", ownerID, repoID).
+		OrderBy("name").
+		Find(&envs); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+// ErrEnvironmentAlreadyExists is returned by CreateEnvironment when ownerID/repoID
+// already has an environment with that name.
+type ErrEnvironmentAlreadyExists struct {
+	Name string
+}
+
+func (e ErrEnvironmentAlreadyExists) Error() string {
+	return "environment " + e.Name + " already exists"
+}
+
+// CreateEnvironment registers a new deployment environment. Pass repoID == 0 to declare
+// it org-wide, visible to every repo owned by ownerID.
+func CreateEnvironment(ctx context.Context, ownerID, repoID int64, name string) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{OwnerID: ownerID, RepoID: repoID, Name: name}
+
+	// The pre-check alone can't close the race: two concurrent requests for the same name
+	// can both see "not exists" before either commits, no matter the isolation level. The
+	// UNIQUE(s) constraint is the real arbiter, so a duplicate-key error out of Insert is
+	// translated the same way a failed pre-check is, instead of leaking to the caller as a
+	// raw DB error.
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		exists, err := db.GetEngine(ctx).Exist(&ActionEnvironment{OwnerID: ownerID, RepoID: repoID, Name: name})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrEnvironmentAlreadyExists{Name: name}
+		}
+		if _, err := db.GetEngine(ctx).Insert(env); err != nil {
+			if isDuplicateKeyErr(err) {
+				return ErrEnvironmentAlreadyExists{Name: name}
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// duplicateKeyErrPhrases are the unique-constraint-violation messages emitted by the
+// database drivers xorm supports here. Matched against the full error text rather than a
+// typed driver error since none of those driver packages are available to import from
+// this code; phrases are specific enough that a colliding environment/workflow name can't
+// trigger a false positive the way a bare "unique"/"duplicate" substring check could.
+var duplicateKeyErrPhrases = []string{
+	"unique constraint failed",                       // sqlite3
+	"duplicate entry",                                // mysql
+	"duplicate key value violates unique constraint", // postgres
+}
+
+// isDuplicateKeyErr reports whether err is the database's unique-constraint violation,
+// however the driver in use happens to phrase it.
+func isDuplicateKeyErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, phrase := range duplicateKeyErrPhrases {
+		if strings.Contains(msg, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteEnvironment removes an environment by ID, scoped to ownerID so one org/user
+// can't delete another's rows.
+func DeleteEnvironment(ctx context.Context, ownerID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(&ActionEnvironment{ID: id})
+	return err
+}
+
+// DeleteRepoEnvironment removes an environment by ID, scoped to repoID. It only matches
+// environments declared directly on the repo; org-wide environments (RepoID == 0) must be
+// removed through the org-level endpoint instead.
+func DeleteRepoEnvironment(ctx context.Context, repoID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(&ActionEnvironment{ID: id})
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// RequireAction records that every member repository of OrgID must also run the
+// workflow named WorkflowName, sourced from RepoName (a `.workflow` repository
+// belonging to the same org, unless AllowAnyRepo is set).
+type RequireAction struct {
+	ID           int64  `xorm:"pk autoincr"`
+	OrgID        int64  `xorm:"UNIQUE(s) index"`
+	RepoName     string `xorm:"UNIQUE(s) NOT NULL"`
+	WorkflowName string `xorm:"UNIQUE(s) NOT NULL"`
+	AllowAnyRepo bool
+}
+
+func init() {
+	db.RegisterModel(new(RequireAction))
+}
+
+// FindRequireActionOptions filters the RequireAction rows for an organization.
+type FindRequireActionOptions struct {
+	db.ListOptions
+	OrgID int64
+}
+
+func (opts FindRequireActionOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OrgID > 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
+	}
+	return cond
+}
+
+// ErrRequireActionSourceInvalid is returned when a RequireAction's source repo isn't
+// eligible to define org-required workflows.
+type ErrRequireActionSourceInvalid struct {
+	RepoName string
+}
+
+func (e ErrRequireActionSourceInvalid) Error() string {
+	return fmt.Sprintf("repo %q must end in \".workflow\" or be explicitly allowed to source required workflows", e.RepoName)
+}
+
+// CreateRequireAction registers a new org-required workflow. repoName must end in
+// ".workflow" unless allowAnyRepo is set.
+func CreateRequireAction(ctx context.Context, orgID int64, repoName, workflowName string, allowAnyRepo bool) (*RequireAction, error) {
+	if !allowAnyRepo && !strings.HasSuffix(repoName, ".workflow") {
+		return nil, ErrRequireActionSourceInvalid{RepoName: repoName}
+	}
+
+	require := &RequireAction{
+		OrgID:        orgID,
+		RepoName:     repoName,
+		WorkflowName: workflowName,
+		AllowAnyRepo: allowAnyRepo,
+	}
+	if err := db.Insert(ctx, require); err != nil {
+		return nil, err
+	}
+	return require, nil
+}
+
+// DeleteRequireAction removes an org-required workflow by ID, scoped to orgID so one
+// org can't delete another's rows.
+func DeleteRequireAction(ctx context.Context, orgID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(&RequireAction{ID: id})
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// Record is a single persisted audit-log entry.
+type Record struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Action      string             `xorm:"INDEX NOT NULL"`
+	DoerID      int64              `xorm:"INDEX"`
+	RepoID      int64              `xorm:"INDEX"`
+	Data        string             `xorm:"TEXT"` // JSON-encoded, action-specific
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(Record))
+}
+
+// CreateRecord persists a single audit-log entry.
+func CreateRecord(ctx context.Context, action string, doerID, repoID int64, data string) error {
+	_, err := db.GetEngine(ctx).Insert(&Record{
+		Action: action,
+		DoerID: doerID,
+		RepoID: repoID,
+		Data:   data,
+	})
+	return err
+}
+
+// FindRecords returns the audit-log entries for repoID, most recent first.
+func FindRecords(ctx context.Context, repoID int64) ([]*Record, error) {
+	var records []*Record
+	if err := db.GetEngine(ctx).
+		Where("repo_id = ?", repoID).
+		OrderBy("id DESC").
+		Find(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"sync"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// maxWorkflowIndexEntries bounds GlobalWorkflowIndex so it can't grow without limit on
+// an instance where nothing ever calls Sweep. It's generous enough to hold every
+// workflow file of a repo with hundreds of active branches.
+const maxWorkflowIndexEntries = 4096
+
+// WorkflowIndexKey identifies one memoized workflow parse. A blob's content (and so its
+// EntrySHA) never changes, so the same workflow file reused across many commits on a
+// branch is only ever parsed once.
+type WorkflowIndexKey struct {
+	RepoID   int64
+	EntrySHA string
+}
+
+// WorkflowIndexEntry is everything List needs from a parsed workflow, computed once per
+// (repo, workflow file) instead of on every page view.
+type WorkflowIndexEntry struct {
+	Workflow           *model.Workflow
+	Labels             container.Set[string]
+	HasJobWithoutNeeds bool
+	EmptyJobs          bool
+	ParseErr           error
+}
+
+// WorkflowIndex memoizes parsed workflows and their derived runner-label set, keyed by
+// (repoID, entrySHA).
+type WorkflowIndex struct {
+	mu      sync.RWMutex
+	entries map[WorkflowIndexKey]*WorkflowIndexEntry
+	// order records insertion order so Get can evict the oldest entry once the cache is full.
+	order []WorkflowIndexKey
+}
+
+// NewWorkflowIndex creates an empty WorkflowIndex.
+func NewWorkflowIndex() *WorkflowIndex {
+	return &WorkflowIndex{
+		entries: make(map[WorkflowIndexKey]*WorkflowIndexEntry),
+	}
+}
+
+// GlobalWorkflowIndex is the process-wide cache used by the actions list page.
+var GlobalWorkflowIndex = NewWorkflowIndex()
+
+// Get returns the memoized entry for key, parsing and caching it via loadContent on a
+// miss. loadContent is only called when the entry isn't already cached. An error from
+// loadContent is returned as is with a nil entry and is never cached, so a transient
+// failure (e.g. an object-store read error) is retried on the next call instead of being
+// stuck behind a cached miss. A parse error is cached in the entry's ParseErr and is
+// returned together with that entry on every call.
+func (idx *WorkflowIndex) Get(key WorkflowIndexKey, loadContent func() ([]byte, error)) (*WorkflowIndexEntry, error) {
+	idx.mu.RLock()
+	entry, ok := idx.entries[key]
+	idx.mu.RUnlock()
+	if ok {
+		return entry, entry.ParseErr
+	}
+
+	content, err := loadContent()
+	if err != nil {
+		return nil, err
+	}
+
+	entry = parseWorkflowIndexEntry(content)
+	idx.set(key, entry)
+
+	return entry, entry.ParseErr
+}
+
+func (idx *WorkflowIndex) set(key WorkflowIndexKey, entry *WorkflowIndexEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, exists := idx.entries[key]; !exists {
+		idx.order = append(idx.order, key)
+	}
+	idx.entries[key] = entry
+
+	for len(idx.order) > maxWorkflowIndexEntries {
+		oldest := idx.order[0]
+		idx.order = idx.order[1:]
+		delete(idx.entries, oldest)
+	}
+}
+
+func parseWorkflowIndexEntry(content []byte) *WorkflowIndexEntry {
+	wf, err := model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return &WorkflowIndexEntry{ParseErr: err}
+	}
+
+	entry := &WorkflowIndexEntry{
+		Workflow: wf,
+		Labels:   make(container.Set[string]),
+	}
+
+	emptyJobs := 0
+	for _, j := range wf.Jobs {
+		if j == nil {
+			emptyJobs++
+			continue
+		}
+		if !entry.HasJobWithoutNeeds && len(j.Needs()) == 0 {
+			entry.HasJobWithoutNeeds = true
+		}
+		entry.Labels.AddMultiple(j.RunsOn()...)
+	}
+	entry.EmptyJobs = emptyJobs == len(wf.Jobs)
+
+	return entry
+}
+
+// Sweep evicts every cached entry for repoID. It is meant to be called when a repo is
+// deleted or wiped, since its workflow blobs can otherwise never be evicted by content
+// alone; the size cap in Get/set handles steady-state growth.
+func (idx *WorkflowIndex) Sweep(repoID int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	remaining := idx.order[:0]
+	for _, key := range idx.order {
+		if key.RepoID == repoID {
+			delete(idx.entries, key)
+			continue
+		}
+		remaining = append(remaining, key)
+	}
+	idx.order = remaining
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleWorkflow = `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`
+
+func TestWorkflowIndex_GetCachesOnHit(t *testing.T) {
+	idx := NewWorkflowIndex()
+	calls := 0
+	loader := func() ([]byte, error) {
+		calls++
+		return []byte(sampleWorkflow), nil
+	}
+
+	key := WorkflowIndexKey{RepoID: 1, EntrySHA: "deadbeef"}
+	first, err := idx.Get(key, loader)
+	assert.NoError(t, err)
+	assert.NotNil(t, first)
+	assert.Equal(t, 1, calls)
+
+	second, err := idx.Get(key, loader)
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, calls, "loader must not run again on a cache hit")
+}
+
+func TestWorkflowIndex_ParseErrIsNotCachedAsSuccess(t *testing.T) {
+	idx := NewWorkflowIndex()
+	key := WorkflowIndexKey{RepoID: 1, EntrySHA: "bad"}
+
+	_, err := idx.Get(key, func() ([]byte, error) {
+		return []byte("not: [valid"), nil
+	})
+	assert.Error(t, err)
+
+	_, err = idx.Get(key, func() ([]byte, error) {
+		return []byte(sampleWorkflow), nil
+	})
+	assert.Error(t, err, "a cached parse error must still be reported on a hit")
+}
+
+func TestWorkflowIndex_SetEvictsOldestBeyondCap(t *testing.T) {
+	idx := NewWorkflowIndex()
+	for i := 0; i < maxWorkflowIndexEntries+10; i++ {
+		key := WorkflowIndexKey{RepoID: 1, EntrySHA: fmt.Sprintf("sha-%d", i)}
+		idx.set(key, &WorkflowIndexEntry{})
+	}
+
+	assert.LessOrEqual(t, len(idx.entries), maxWorkflowIndexEntries)
+	assert.LessOrEqual(t, len(idx.order), maxWorkflowIndexEntries)
+
+	_, ok := idx.entries[WorkflowIndexKey{RepoID: 1, EntrySHA: "sha-0"}]
+	assert.False(t, ok, "oldest entry should have been evicted")
+}
+
+func TestWorkflowIndex_Sweep(t *testing.T) {
+	idx := NewWorkflowIndex()
+	idx.set(WorkflowIndexKey{RepoID: 1, EntrySHA: "a"}, &WorkflowIndexEntry{})
+	idx.set(WorkflowIndexKey{RepoID: 2, EntrySHA: "b"}, &WorkflowIndexEntry{})
+
+	idx.Sweep(1)
+
+	_, ok := idx.entries[WorkflowIndexKey{RepoID: 1, EntrySHA: "a"}]
+	assert.False(t, ok)
+	_, ok = idx.entries[WorkflowIndexKey{RepoID: 2, EntrySHA: "b"}]
+	assert.True(t, ok)
+}
+
+// BenchmarkWorkflowIndex_ColdParse simulates the actions list page's first render of a
+// repo with 64 workflow files: every entry misses and has to be parsed.
+func BenchmarkWorkflowIndex_ColdParse(b *testing.B) {
+	const workflowCount = 64
+	for i := 0; i < b.N; i++ {
+		idx := NewWorkflowIndex()
+		for j := 0; j < workflowCount; j++ {
+			key := WorkflowIndexKey{RepoID: 1, EntrySHA: fmt.Sprintf("sha-%d", j)}
+			_, _ = idx.Get(key, func() ([]byte, error) {
+				return []byte(sampleWorkflow), nil
+			})
+		}
+	}
+}
+
+// BenchmarkWorkflowIndex_WarmCache simulates every subsequent render of the same list
+// page: every entry hits, so the workflow YAML is never re-parsed.
+func BenchmarkWorkflowIndex_WarmCache(b *testing.B) {
+	const workflowCount = 64
+	idx := NewWorkflowIndex()
+	keys := make([]WorkflowIndexKey, workflowCount)
+	for j := 0; j < workflowCount; j++ {
+		keys[j] = WorkflowIndexKey{RepoID: 1, EntrySHA: fmt.Sprintf("sha-%d", j)}
+		_, _ = idx.Get(keys[j], func() ([]byte, error) {
+			return []byte(sampleWorkflow), nil
+		})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			_, _ = idx.Get(key, func() ([]byte, error) {
+				b.Fatal("loadContent must not run on a warm cache")
+				return nil, nil
+			})
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "encoding/json"
+
+// WorkflowDispatchPayload contains the information for a workflow_dispatch webhook event
+type WorkflowDispatchPayload struct {
+	Workflow   string            `json:"workflow"`
+	Ref        string            `json:"ref"`
+	Inputs     map[string]string `json:"inputs"`
+	Sender     *User             `json:"sender"`
+	Repository *Repository       `json:"repository"`
+}
+
+// JSONPayload implements Payloader
+func (p *WorkflowDispatchPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// ActionsRequiredWorkflow represents a workflow an organization requires every member
+// repository to run
+type ActionsRequiredWorkflow struct {
+	ID           int64  `json:"id"`
+	RepoName     string `json:"repo_name"`
+	WorkflowName string `json:"workflow_name"`
+}
+
+// CreateActionsRequiredWorkflowOption is the payload to register a new org-required workflow
+type CreateActionsRequiredWorkflowOption struct {
+	// name of the `.workflow` repository the workflow is sourced from
+	// required: true
+	RepoName string `json:"repo_name" binding:"Required"`
+	// name of the workflow file within RepoName
+	// required: true
+	WorkflowName string `json:"workflow_name" binding:"Required"`
+	// allow RepoName to not end in ".workflow"
+	AllowAnyRepo bool `json:"allow_any_repo"`
+}
+
+// ActionsEnvironment represents a deployment environment that workflow_dispatch inputs
+// of type `environment` can resolve against
+type ActionsEnvironment struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateActionsEnvironmentOption is the payload to register a new deployment environment
+type CreateActionsEnvironmentOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+}
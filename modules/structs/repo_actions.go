@@ -0,0 +1,13 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// CreateActionWorkflowDispatch represents the payload of a workflow_dispatch request
+type CreateActionWorkflowDispatch struct {
+	// Git ref the workflow should run on, e.g. a branch or tag name
+	// required: true
+	Ref string `json:"ref" binding:"Required"`
+	// Inputs keyed by the names declared in the workflow's `workflow_dispatch.inputs`
+	Inputs map[string]string `json:"inputs"`
+}
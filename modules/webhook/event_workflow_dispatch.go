@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+// HookEventWorkflowDispatch is fired when a workflow_dispatch run is triggered manually,
+// either from the "Run workflow" web form or the REST API.
+const HookEventWorkflowDispatch HookEventType = "workflow_dispatch"
+
+// WorkflowDispatch reports whether a webhook has opted in to HookEventWorkflowDispatch,
+// either by subscribing to everything or by ticking the individual event checkbox under
+// its settings page.
+func (e HookEvent) WorkflowDispatch() bool {
+	return e.SendEverything || (e.ChooseEvents && e.HookEvents.WorkflowDispatch)
+}
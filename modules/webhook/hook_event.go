@@ -0,0 +1,39 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+// HookEventType is the kind of repository event a webhook can be configured to deliver.
+type HookEventType string
+
+const (
+	HookEventCreate      HookEventType = "create"
+	HookEventDelete      HookEventType = "delete"
+	HookEventPush        HookEventType = "push"
+	HookEventPullRequest HookEventType = "pull_request"
+	HookEventRepository  HookEventType = "repository"
+	HookEventRelease     HookEventType = "release"
+)
+
+// HookEvents is the set of events a webhook has individually opted into. It is only
+// consulted when the owning HookEvent has ChooseEvents set; SendEverything bypasses it.
+type HookEvents struct {
+	Create           bool `json:"create"`
+	Delete           bool `json:"delete"`
+	Push             bool `json:"push"`
+	PullRequest      bool `json:"pull_request"`
+	Repository       bool `json:"repository"`
+	Release          bool `json:"release"`
+	WorkflowDispatch bool `json:"workflow_dispatch"`
+}
+
+// HookEvent is the event-subscription portion of a webhook's configuration: either it
+// fires on every event (SendEverything), or only those individually ticked in HookEvents
+// (ChooseEvents).
+type HookEvent struct {
+	PushOnly       bool `json:"push_only"`
+	SendEverything bool `json:"send_everything"`
+	ChooseEvents   bool `json:"choose_events"`
+
+	HookEvents `json:"events"`
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRequiredWorkflows lists the workflows this org requires every member repo to run.
+func ListRequiredWorkflows(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/required-workflows organization orgListRequiredWorkflows
+	// ---
+	// summary: List required workflows of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RequiredWorkflowList"
+	requires, err := db.Find[actions_model.RequireAction](ctx, actions_model.FindRequireActionOptions{
+		OrgID: ctx.Org.Organization.ID,
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	result := make([]*api.ActionsRequiredWorkflow, 0, len(requires))
+	for _, r := range requires {
+		result = append(result, &api.ActionsRequiredWorkflow{
+			ID:           r.ID,
+			RepoName:     r.RepoName,
+			WorkflowName: r.WorkflowName,
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateRequiredWorkflow adds a workflow to the org's required workflows.
+func CreateRequiredWorkflow(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/required-workflows organization orgCreateRequiredWorkflow
+	// ---
+	// summary: Add a required workflow to an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionsRequiredWorkflowOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RequiredWorkflow"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opt := web.GetForm(ctx).(*api.CreateActionsRequiredWorkflowOption)
+
+	require, err := actions_model.CreateRequireAction(ctx, ctx.Org.Organization.ID, opt.RepoName, opt.WorkflowName, opt.AllowAnyRepo)
+	if err != nil {
+		if _, ok := err.(actions_model.ErrRequireActionSourceInvalid); ok {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+			return
+		}
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ActionsRequiredWorkflow{
+		ID:           require.ID,
+		RepoName:     require.RepoName,
+		WorkflowName: require.WorkflowName,
+	})
+}
+
+// DeleteRequiredWorkflow removes one of the org's required workflows.
+func DeleteRequiredWorkflow(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/required-workflows/{id} organization orgDeleteRequiredWorkflow
+	// ---
+	// summary: Remove a required workflow from an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the required workflow
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	if err := actions_model.DeleteRequireAction(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("id")); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListActionsEnvironments lists the org-wide deployment environments `workflow_dispatch`
+// inputs of type `environment` can resolve against.
+func ListActionsEnvironments(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/environments organization orgListActionsEnvironments
+	// ---
+	// summary: List an organization's deployment environments
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsEnvironmentList"
+	envs, err := actions_model.FindEnvironments(ctx, ctx.Org.Organization.ID, 0)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	result := make([]*api.ActionsEnvironment, 0, len(envs))
+	for _, env := range envs {
+		result = append(result, &api.ActionsEnvironment{ID: env.ID, Name: env.Name})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateActionsEnvironment adds an org-wide deployment environment.
+func CreateActionsEnvironment(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/environments organization orgCreateActionsEnvironment
+	// ---
+	// summary: Add a deployment environment to an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionsEnvironmentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionsEnvironment"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opt := web.GetForm(ctx).(*api.CreateActionsEnvironmentOption)
+
+	env, err := actions_model.CreateEnvironment(ctx, ctx.Org.Organization.ID, 0, opt.Name)
+	if err != nil {
+		if _, ok := err.(actions_model.ErrEnvironmentAlreadyExists); ok {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+			return
+		}
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ActionsEnvironment{ID: env.ID, Name: env.Name})
+}
+
+// DeleteActionsEnvironment removes one of the org's deployment environments.
+func DeleteActionsEnvironment(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/environments/{id} organization orgDeleteActionsEnvironment
+	// ---
+	// summary: Remove a deployment environment from an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the environment
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	if err := actions_model.DeleteEnvironment(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64("id")); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/unit"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// DispatchWorkflow dispatches a workflow_dispatch event for the given workflow, mirroring
+// POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches on the GitHub API.
+func DispatchWorkflow(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches repository actionDispatchWorkflow
+	// ---
+	// summary: Create a workflow dispatch event
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: name of the workflow file, e.g. build.yaml
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionWorkflowDispatch"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opt := web.GetForm(ctx).(*api.CreateActionWorkflowDispatch)
+
+	if !ctx.Repo.CanWrite(unit.TypeActions) {
+		ctx.APIErrorNotFound()
+		return
+	}
+
+	workflowID := ctx.PathParam("workflow_id")
+
+	err := actions_service.DispatchWorkflow(ctx, ctx.Repo.Repository, ctx.Doer, workflowID, opt.Ref, opt.Inputs)
+	if err != nil {
+		if actions_service.IsErrInvalidDispatchInput(err) || actions_service.IsInputRequiredErr(err) {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+			return
+		}
+		if util.IsErrNotExist(err) {
+			ctx.APIErrorNotFound()
+			return
+		}
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
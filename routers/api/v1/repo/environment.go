@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListActionsEnvironments lists the deployment environments visible to this repo, both
+// its own and any declared org-wide by its owner.
+func ListActionsEnvironments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/environments repository repoListActionsEnvironments
+	// ---
+	// summary: List a repository's deployment environments
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsEnvironmentList"
+	envs, err := actions_model.FindEnvironments(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	result := make([]*api.ActionsEnvironment, 0, len(envs))
+	for _, env := range envs {
+		result = append(result, &api.ActionsEnvironment{ID: env.ID, Name: env.Name})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateActionsEnvironment adds a deployment environment scoped to this repo.
+func CreateActionsEnvironment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/environments repository repoCreateActionsEnvironment
+	// ---
+	// summary: Add a deployment environment to a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionsEnvironmentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionsEnvironment"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opt := web.GetForm(ctx).(*api.CreateActionsEnvironmentOption)
+
+	env, err := actions_model.CreateEnvironment(ctx, ctx.Repo.Repository.OwnerID, ctx.Repo.Repository.ID, opt.Name)
+	if err != nil {
+		if _, ok := err.(actions_model.ErrEnvironmentAlreadyExists); ok {
+			ctx.APIError(http.StatusUnprocessableEntity, err)
+			return
+		}
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ActionsEnvironment{ID: env.ID, Name: env.Name})
+}
+
+// DeleteActionsEnvironment removes one of this repo's deployment environments. It cannot
+// remove an environment declared org-wide by the repo's owner; use the org-level endpoint
+// for that.
+func DeleteActionsEnvironment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/environments/{id} repository repoDeleteActionsEnvironment
+	// ---
+	// summary: Remove a deployment environment from a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the environment
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	if err := actions_model.DeleteRepoEnvironment(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("id")); err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
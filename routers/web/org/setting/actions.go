@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplOrgActions base.TplName = "org/settings/actions"
+
+// RequiredWorkflows renders the org settings page listing its required workflows.
+func RequiredWorkflows(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.actions")
+	ctx.Data["PageIsOrgSettingsActions"] = true
+
+	requires, err := db.Find[actions_model.RequireAction](ctx, actions_model.FindRequireActionOptions{
+		OrgID: ctx.Org.Organization.ID,
+	})
+	if err != nil {
+		ctx.ServerError("FindRequireAction", err)
+		return
+	}
+	ctx.Data["RequiredWorkflows"] = requires
+
+	ctx.HTML(http.StatusOK, tplOrgActions)
+}
+
+// RequiredWorkflowsPost adds a required workflow from the org settings form.
+func RequiredWorkflowsPost(ctx *context.Context) {
+	repoName := ctx.FormString("repo_name")
+	workflowName := ctx.FormString("workflow_name")
+	allowAnyRepo := ctx.FormBool("allow_any_repo")
+
+	if _, err := actions_model.CreateRequireAction(ctx, ctx.Org.Organization.ID, repoName, workflowName, allowAnyRepo); err != nil {
+		if _, ok := err.(actions_model.ErrRequireActionSourceInvalid); ok {
+			ctx.Flash.Error(err.Error())
+			ctx.Redirect(ctx.Org.OrgLink + "/settings/actions")
+			return
+		}
+		ctx.ServerError("CreateRequireAction", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.update_settings_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/actions")
+}
+
+// RequiredWorkflowDelete removes a required workflow from the org settings page.
+func RequiredWorkflowDelete(ctx *context.Context) {
+	id := ctx.FormInt64("id")
+	if err := actions_model.DeleteRequireAction(ctx, ctx.Org.Organization.ID, id); err != nil {
+		ctx.ServerError("DeleteRequireAction", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.update_settings_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/actions")
+}
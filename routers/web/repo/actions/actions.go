@@ -4,9 +4,9 @@
 package actions
 
 import (
-	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 
@@ -19,17 +19,16 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
-	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 
 	"github.com/nektos/act/pkg/model"
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -43,6 +42,14 @@ type Workflow struct {
 	ErrMsg string
 }
 
+// workflowSource pairs a workflow tree entry with the repo it actually lives in,
+// which for an org-required workflow differs from the repo whose page is being rendered.
+type workflowSource struct {
+	entry  *git.TreeEntry
+	repoID int64
+	global bool
+}
+
 // MustEnableActions check if actions are enabled in settings
 func MustEnableActions(ctx *context.Context) {
 	if !setting.Actions.Enabled {
@@ -73,19 +80,9 @@ func List(ctx *context.Context) {
 
 	var workflows []Workflow
 	var curWorkflow *model.Workflow
-	var globalEntries []*git.TreeEntry
-	globalWorkflow, err := db.Find[actions_model.RequireAction](ctx, actions_model.FindRequireActionOptions{
-		OrgID: ctx.Repo.Repository.Owner.ID,
-	})
-	if err != nil {
-		ctx.ServerError("Global Workflow DB find fail", err)
-		return
-	}
 	if empty, err := ctx.Repo.GitRepo.IsEmpty(); err != nil {
 		ctx.ServerError("IsEmpty", err)
-		if len(globalWorkflow) < 1 {
-			return
-		}
+		return
 	} else if !empty {
 		commit, err := ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
 		if err != nil {
@@ -97,25 +94,31 @@ func List(ctx *context.Context) {
 			ctx.ServerError("ListWorkflows", err)
 			return
 		}
-		for _, gEntry := range globalWorkflow {
-			if gEntry.RepoName == ctx.Repo.Repository.Name {
-				log.Trace("Same Repo conflict: %s\n", gEntry.RepoName)
+
+		localNames := make(container.Set[string])
+		for _, entry := range entries {
+			localNames.Add(entry.Name())
+		}
+
+		sources := make([]workflowSource, 0, len(entries))
+		for _, entry := range entries {
+			sources = append(sources, workflowSource{entry: entry, repoID: ctx.Repo.Repository.ID})
+		}
+
+		required, err := actions_service.ResolveRequiredWorkflows(ctx, ctx.Repo.Repository)
+		if err != nil {
+			ctx.ServerError("ResolveRequiredWorkflows", err)
+			return
+		}
+		for _, rw := range required {
+			if localNames.Contains(rw.Entry.Name()) {
+				// the repo redefines this workflow name itself; its own copy wins
 				continue
 			}
-			gRepo, _ := repo_model.GetRepositoryByName(ctx, gEntry.OrgID, gEntry.RepoName)
-			gGitRepo, _ := gitrepo.OpenRepository(git.DefaultContext, gRepo)
-			// it may be a hack for now..... not sure any better way to do this
-			gCommit, _ := gGitRepo.GetBranchCommit(gRepo.DefaultBranch)
-			gEntries, _ := actions.ListWorkflows(gCommit)
-			for _, entry := range gEntries {
-				if gEntry.WorkflowName == entry.Name() {
-					globalEntries = append(globalEntries, entry)
-					entries = append(entries, entry)
-				}
-			}
+			sources = append(sources, workflowSource{entry: rw.Entry, repoID: rw.SourceRepo.ID, global: true})
 		}
 
-		// Get all runner labels
+		// Get all runner labels, once per request
 		runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
 			RepoID:        ctx.Repo.Repository.ID,
 			IsOnline:      optional.Some(true),
@@ -130,66 +133,49 @@ func List(ctx *context.Context) {
 			allRunnerLabels.AddMultiple(r.AgentLabels...)
 		}
 
-		workflows = make([]Workflow, 0, len(entries))
-		for _, entry := range entries {
-			var workflowIsGlobal bool
-			workflowIsGlobal = false
-			for i := range globalEntries {
-				if globalEntries[i] == entry {
-					workflowIsGlobal = true
+		workflows = make([]Workflow, 0, len(sources))
+		for _, src := range sources {
+			entry := src.entry
+			workflow := Workflow{Entry: *entry, Global: src.global}
+
+			key := actions.WorkflowIndexKey{RepoID: src.repoID, EntrySHA: entry.ID.String()}
+			indexed, loadErr := actions.GlobalWorkflowIndex.Get(key, func() ([]byte, error) {
+				return actions.GetContentFromEntry(entry)
+			})
+			if loadErr != nil {
+				if indexed == nil {
+					// loadContent itself failed (e.g. object read error), as opposed to a bad
+					// workflow file, so this is worth a server-side log even though we still
+					// degrade gracefully for the user.
+					log.Error("GetContentFromEntry for workflow %q in %s: %v", entry.Name(), ctx.Repo.Repository.FullName(), loadErr)
 				}
-			}
-			workflow := Workflow{Entry: *entry, Global: workflowIsGlobal}
-			content, err := actions.GetContentFromEntry(entry)
-			if err != nil {
-				ctx.ServerError("GetContentFromEntry", err)
-				return
-			}
-			wf, err := model.ReadWorkflow(bytes.NewReader(content))
-			if err != nil {
-				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.invalid_workflow_helper", err.Error())
+				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.invalid_workflow_helper", loadErr.Error())
 				workflows = append(workflows, workflow)
 				continue
 			}
-			// The workflow must contain at least one job without "needs". Otherwise, a deadlock will occur and no jobs will be able to run.
-			hasJobWithoutNeeds := false
-			// Check whether have matching runner and a job without "needs"
-			emptyJobsNumber := 0
-			for _, j := range wf.Jobs {
-				if j == nil {
-					emptyJobsNumber++
+
+			for ro := range indexed.Labels {
+				if strings.Contains(ro, "${{") {
+					// Skip if it contains expressions.
+					// The expressions could be very complex and could not be evaluated here,
+					// so just skip it, it's OK since it's just a tooltip message.
 					continue
 				}
-				if !hasJobWithoutNeeds && len(j.Needs()) == 0 {
-					hasJobWithoutNeeds = true
-				}
-				runsOnList := j.RunsOn()
-				for _, ro := range runsOnList {
-					if strings.Contains(ro, "${{") {
-						// Skip if it contains expressions.
-						// The expressions could be very complex and could not be evaluated here,
-						// so just skip it, it's OK since it's just a tooltip message.
-						continue
-					}
-					if !allRunnerLabels.Contains(ro) {
-						workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_matching_online_runner_helper", ro)
-						break
-					}
-				}
-				if workflow.ErrMsg != "" {
+				if !allRunnerLabels.Contains(ro) {
+					workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_matching_online_runner_helper", ro)
 					break
 				}
 			}
-			if !hasJobWithoutNeeds {
+			if workflow.ErrMsg == "" && !indexed.HasJobWithoutNeeds {
 				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_job_without_needs")
 			}
-			if emptyJobsNumber == len(wf.Jobs) {
+			if indexed.EmptyJobs {
 				workflow.ErrMsg = ctx.Locale.TrString("actions.runs.no_job")
 			}
 			workflows = append(workflows, workflow)
 
 			if workflow.Entry.Name() == workflowID {
-				curWorkflow = wf
+				curWorkflow = indexed.Workflow
 			}
 		}
 	}
@@ -218,7 +204,7 @@ func List(ctx *context.Context) {
 		ctx.Data["CurWorkflowDisabled"] = isWorkflowDisabled
 
 		if !isWorkflowDisabled && curWorkflow != nil {
-			workflowDispatchConfig := workflowDispatchConfig(curWorkflow)
+			workflowDispatchConfig := actions_service.WorkflowDispatchConfig(curWorkflow)
 			if workflowDispatchConfig != nil {
 				ctx.Data["WorkflowDispatchConfig"] = workflowDispatchConfig
 
@@ -317,85 +303,45 @@ func List(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplListActions)
 }
 
-type WorkflowDispatchInput struct {
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	Required    bool     `yaml:"required"`
-	Default     string   `yaml:"default"`
-	Type        string   `yaml:"type"`
-	Options     []string `yaml:"options"`
-}
-
-type WorkflowDispatch struct {
-	Inputs []WorkflowDispatchInput
-}
+// Run handles the "Run workflow" form on the actions list page, dispatching a
+// workflow_dispatch event for the selected workflow and ref.
+func Run(ctx *context.Context) {
+	workflowID := ctx.FormString("workflow")
+	ref := ctx.FormString("ref")
+	redirect := fmt.Sprintf("%s/actions?workflow=%s", ctx.Repo.Repository.Link(), url.QueryEscape(workflowID))
 
-func workflowDispatchConfig(w *model.Workflow) *WorkflowDispatch {
-	switch w.RawOn.Kind {
-	case yaml.ScalarNode:
-		var val string
-		if !decodeNode(w.RawOn, &val) {
-			return nil
-		}
-		if val == "workflow_dispatch" {
-			return &WorkflowDispatch{}
-		}
-	case yaml.SequenceNode:
-		var val []string
-		if !decodeNode(w.RawOn, &val) {
-			return nil
-		}
-		for _, v := range val {
-			if v == "workflow_dispatch" {
-				return &WorkflowDispatch{}
-			}
-		}
-	case yaml.MappingNode:
-		var val map[string]yaml.Node
-		if !decodeNode(w.RawOn, &val) {
-			return nil
-		}
+	if !ctx.Repo.CanWrite(unit.TypeActions) {
+		ctx.NotFound("Run", nil)
+		return
+	}
 
-		workflowDispatchNode, found := val["workflow_dispatch"]
-		if !found {
-			return nil
+	inputs := make(map[string]string)
+	for name := range ctx.Req.Form {
+		rest, ok := strings.CutPrefix(name, "inputs[")
+		if !ok {
+			continue
 		}
-
-		var workflowDispatch WorkflowDispatch
-		var workflowDispatchVal map[string]yaml.Node
-		if !decodeNode(workflowDispatchNode, &workflowDispatchVal) {
-			return &workflowDispatch
+		key, ok := strings.CutSuffix(rest, "]")
+		if !ok {
+			continue
 		}
+		inputs[key] = ctx.FormString(name)
+	}
 
-		inputsNode, found := workflowDispatchVal["inputs"]
-		if !found || inputsNode.Kind != yaml.MappingNode {
-			return &workflowDispatch
+	if err := actions_service.DispatchWorkflow(ctx, ctx.Repo.Repository, ctx.Doer, workflowID, ref, inputs); err != nil {
+		if actions_service.IsErrInvalidDispatchInput(err) || actions_service.IsInputRequiredErr(err) {
+			ctx.Flash.Error(err.Error())
+			ctx.Redirect(redirect)
+			return
 		}
-
-		i := 0
-		for {
-			if i+1 >= len(inputsNode.Content) {
-				break
-			}
-			var input WorkflowDispatchInput
-			if decodeNode(*inputsNode.Content[i+1], &input) {
-				input.Name = inputsNode.Content[i].Value
-				workflowDispatch.Inputs = append(workflowDispatch.Inputs, input)
-			}
-			i += 2
+		if util.IsErrNotExist(err) {
+			ctx.NotFound("DispatchWorkflow", err)
+			return
 		}
-		return &workflowDispatch
-
-	default:
-		return nil
+		ctx.ServerError("DispatchWorkflow", err)
+		return
 	}
-	return nil
-}
 
-func decodeNode(node yaml.Node, out any) bool {
-	if err := node.Decode(out); err != nil {
-		log.Warn("Failed to decode node %v into %T: %v", node, out, err)
-		return false
-	}
-	return true
+	ctx.Flash.Success(ctx.Tr("actions.workflow.dispatch.success"))
+	ctx.Redirect(redirect)
 }
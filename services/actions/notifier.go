@@ -0,0 +1,91 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// requiredWorkflowNotifier triggers an org's required workflows on push and on pull
+// request activity against the base repo, alongside the repo's own workflow triggering,
+// so RequireAction rows actually run instead of only showing up on the actions list page.
+type requiredWorkflowNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &requiredWorkflowNotifier{}
+
+func init() {
+	notify_service.RegisterNotifier(&requiredWorkflowNotifier{})
+}
+
+// PushCommits triggers any workflows repo's organization requires of it for the pushed ref.
+func (r *requiredWorkflowNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if err := TriggerRequiredWorkflows(ctx, repo, opts.RefFullName, opts.NewCommitID, "push"); err != nil {
+		log.Error("requiredWorkflowNotifier: TriggerRequiredWorkflows(%s): %v", repo.FullName(), err)
+	}
+}
+
+// NewPullRequest triggers the base repo's required workflows for a newly opened pull request.
+func (r *requiredWorkflowNotifier) NewPullRequest(ctx context.Context, pr *issues_model.PullRequest, reviewers []*user_model.User) {
+	r.triggerForPullRequest(ctx, pr)
+}
+
+// PullRequestSynchronized triggers the base repo's required workflows whenever the pull
+// request's head branch gets new commits, mirroring how a push to the branch would.
+func (r *requiredWorkflowNotifier) PullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	r.triggerForPullRequest(ctx, pr)
+}
+
+func (r *requiredWorkflowNotifier) triggerForPullRequest(ctx context.Context, pr *issues_model.PullRequest) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		log.Error("requiredWorkflowNotifier: LoadBaseRepo(%d): %v", pr.BaseRepoID, err)
+		return
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
+	if err != nil {
+		log.Error("requiredWorkflowNotifier: OpenRepository(%s): %v", pr.BaseRepo.FullName(), err)
+		return
+	}
+	defer gitRepo.Close()
+
+	ref := pr.GetGitRefName()
+	commitID, err := gitRepo.GetRefCommitID(ref)
+	if err != nil {
+		log.Error("requiredWorkflowNotifier: GetRefCommitID(%s): %v", ref, err)
+		return
+	}
+
+	if err := TriggerRequiredWorkflows(ctx, pr.BaseRepo, ref, commitID, "pull_request"); err != nil {
+		log.Error("requiredWorkflowNotifier: TriggerRequiredWorkflows(%s): %v", pr.BaseRepo.FullName(), err)
+	}
+}
+
+// workflowIndexNotifier evicts a deleted repo's entries from the process-wide workflow
+// parse cache, since a deleted repo's blobs can never be looked up (and so never evicted
+// by content) again.
+type workflowIndexNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &workflowIndexNotifier{}
+
+func init() {
+	notify_service.RegisterNotifier(&workflowIndexNotifier{})
+}
+
+// DeleteRepository sweeps repo's entries out of the global workflow index.
+func (w *workflowIndexNotifier) DeleteRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) {
+	actions_module.GlobalWorkflowIndex.Sweep(repo.ID)
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ResolvedRequiredWorkflow is a workflow a repository inherited from one of its
+// organization's RequireAction rows, together with where it actually came from.
+type ResolvedRequiredWorkflow struct {
+	Name       string
+	Entry      *git.TreeEntry
+	SourceRepo *repo_model.Repository
+	CommitID   string
+}
+
+// ResolveRequiredWorkflows returns every workflow that repo must run because of an
+// org-level RequireAction, resolved against each source repo's default branch. repo's
+// own tree is not consulted, so a workflow that repo redefines by name is still returned
+// here. The repo's own copy always wins, so callers should merge repo-local entries first
+// and skip any resolved entry whose Entry.Name() they already have.
+//
+// Results are cached for the lifetime of ctx, since the list page resolves them once
+// per request per repo.
+func ResolveRequiredWorkflows(ctx context.Context, repo *repo_model.Repository) ([]*ResolvedRequiredWorkflow, error) {
+	return cache.GetContextData(ctx, "actions_required_workflows", fmt.Sprintf("%d", repo.ID), func() ([]*ResolvedRequiredWorkflow, error) {
+		return resolveRequiredWorkflows(ctx, repo)
+	})
+}
+
+func resolveRequiredWorkflows(ctx context.Context, repo *repo_model.Repository) ([]*ResolvedRequiredWorkflow, error) {
+	requires, err := db.Find[actions_model.RequireAction](ctx, actions_model.FindRequireActionOptions{
+		OrgID: repo.OwnerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find require actions: %w", err)
+	}
+
+	resolved := make([]*ResolvedRequiredWorkflow, 0, len(requires))
+	for _, require := range requires {
+		if require.RepoName == repo.Name {
+			log.Trace("ResolveRequiredWorkflows: skipping %s, it is the source repo itself", require.RepoName)
+			continue
+		}
+
+		sourceRepo, err := repo_model.GetRepositoryByName(ctx, require.OrgID, require.RepoName)
+		if err != nil {
+			log.Error("ResolveRequiredWorkflows: GetRepositoryByName(%d, %s): %v", require.OrgID, require.RepoName, err)
+			continue
+		}
+
+		sourceGitRepo, err := gitrepo.OpenRepository(ctx, sourceRepo)
+		if err != nil {
+			log.Error("ResolveRequiredWorkflows: OpenRepository(%s): %v", sourceRepo.FullName(), err)
+			continue
+		}
+
+		commit, err := sourceGitRepo.GetBranchCommit(sourceRepo.DefaultBranch)
+		if err != nil {
+			sourceGitRepo.Close()
+			log.Error("ResolveRequiredWorkflows: GetBranchCommit(%s): %v", sourceRepo.FullName(), err)
+			continue
+		}
+
+		entries, err := actions.ListWorkflows(commit)
+		if err != nil {
+			sourceGitRepo.Close()
+			log.Error("ResolveRequiredWorkflows: ListWorkflows(%s): %v", sourceRepo.FullName(), err)
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.Name() != require.WorkflowName {
+				continue
+			}
+			resolved = append(resolved, &ResolvedRequiredWorkflow{
+				Name:       entry.Name(),
+				Entry:      entry,
+				SourceRepo: sourceRepo,
+				CommitID:   commit.ID.String(),
+			})
+		}
+		sourceGitRepo.Close()
+	}
+
+	return resolved, nil
+}
+
+// TriggerRequiredWorkflows enqueues an ActionRun for every workflow repo requires from
+// its organization, for the given ref/commit/event. It is called from the push and
+// pull_request notifier alongside the repo's own workflow triggering, so org-required
+// workflows actually run instead of only showing up on the actions list page.
+//
+// A required workflow whose name is redefined by repo's own tree at commitSHA is skipped,
+// mirroring the conflict rule List() applies on the actions list page: the repo's own
+// copy always wins, so it must not also be enqueued as a required run.
+func TriggerRequiredWorkflows(ctx context.Context, repo *repo_model.Repository, ref, commitSHA, event string) error {
+	required, err := ResolveRequiredWorkflows(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("ResolveRequiredWorkflows: %w", err)
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	localNames, err := localWorkflowNames(ctx, repo, commitSHA)
+	if err != nil {
+		return fmt.Errorf("localWorkflowNames: %w", err)
+	}
+
+	for _, rw := range filterRequiredByLocalNames(required, localNames) {
+		run := &actions_model.ActionRun{
+			Title:        rw.Name,
+			RepoID:       repo.ID,
+			OwnerID:      repo.OwnerID,
+			WorkflowID:   rw.Name,
+			Ref:          ref,
+			CommitSHA:    commitSHA,
+			Event:        event,
+			TriggerEvent: event,
+			Status:       actions_model.StatusWaiting,
+		}
+		if err := actions_model.InsertRun(ctx, run, nil); err != nil {
+			log.Error("TriggerRequiredWorkflows: InsertRun(%s): %v", rw.Name, err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// filterRequiredByLocalNames drops any required workflow whose name is in localNames,
+// so a repo that redefines a required workflow by name only ever runs its own copy.
+func filterRequiredByLocalNames(required []*ResolvedRequiredWorkflow, localNames container.Set[string]) []*ResolvedRequiredWorkflow {
+	filtered := make([]*ResolvedRequiredWorkflow, 0, len(required))
+	for _, rw := range required {
+		if localNames.Contains(rw.Name) {
+			continue
+		}
+		filtered = append(filtered, rw)
+	}
+	return filtered
+}
+
+// localWorkflowNames returns the names of the workflows repo defines itself at commitSHA,
+// used to skip org-required workflows the repo has redefined locally.
+func localWorkflowNames(ctx context.Context, repo *repo_model.Repository, commitSHA string) (container.Set[string], error) {
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("GetCommit(%s): %w", commitSHA, err)
+	}
+
+	entries, err := actions.ListWorkflows(commit)
+	if err != nil {
+		return nil, fmt.Errorf("ListWorkflows: %w", err)
+	}
+
+	names := make(container.Set[string])
+	for _, entry := range entries {
+		names.Add(entry.Name())
+	}
+	return names, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterRequiredByLocalNames(t *testing.T) {
+	t.Run("no local conflicts keeps everything", func(t *testing.T) {
+		required := []*ResolvedRequiredWorkflow{
+			{Name: "build.yml"},
+			{Name: "deploy.yml"},
+		}
+		filtered := filterRequiredByLocalNames(required, make(container.Set[string]))
+		assert.Len(t, filtered, 2)
+	})
+
+	t.Run("a local redefinition is skipped", func(t *testing.T) {
+		required := []*ResolvedRequiredWorkflow{
+			{Name: "build.yml"},
+			{Name: "deploy.yml"},
+		}
+		local := make(container.Set[string])
+		local.Add("deploy.yml")
+
+		filtered := filterRequiredByLocalNames(required, local)
+		assert.Len(t, filtered, 1)
+		assert.Equal(t, "build.yml", filtered[0].Name)
+	})
+
+	t.Run("empty input stays empty", func(t *testing.T) {
+		assert.Empty(t, filterRequiredByLocalNames(nil, make(container.Set[string])))
+	})
+}
@@ -0,0 +1,321 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowDispatchInput is a single `workflow_dispatch.inputs.*` entry declared by a workflow file.
+type WorkflowDispatchInput struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Default     string   `yaml:"default"`
+	Type        string   `yaml:"type"`
+	Options     []string `yaml:"options"`
+}
+
+// WorkflowDispatch is the parsed `on.workflow_dispatch` section of a workflow file.
+type WorkflowDispatch struct {
+	Inputs []WorkflowDispatchInput
+}
+
+// WorkflowDispatchConfig extracts the `workflow_dispatch` trigger config from a workflow, if any.
+// It returns nil when the workflow does not declare `workflow_dispatch` as one of its triggers.
+func WorkflowDispatchConfig(w *model.Workflow) *WorkflowDispatch {
+	switch w.RawOn.Kind {
+	case yaml.ScalarNode:
+		var val string
+		if !decodeNode(w.RawOn, &val) {
+			return nil
+		}
+		if val == "workflow_dispatch" {
+			return &WorkflowDispatch{}
+		}
+	case yaml.SequenceNode:
+		var val []string
+		if !decodeNode(w.RawOn, &val) {
+			return nil
+		}
+		for _, v := range val {
+			if v == "workflow_dispatch" {
+				return &WorkflowDispatch{}
+			}
+		}
+	case yaml.MappingNode:
+		var val map[string]yaml.Node
+		if !decodeNode(w.RawOn, &val) {
+			return nil
+		}
+
+		workflowDispatchNode, found := val["workflow_dispatch"]
+		if !found {
+			return nil
+		}
+
+		var workflowDispatch WorkflowDispatch
+		var workflowDispatchVal map[string]yaml.Node
+		if !decodeNode(workflowDispatchNode, &workflowDispatchVal) {
+			return &workflowDispatch
+		}
+
+		inputsNode, found := workflowDispatchVal["inputs"]
+		if !found || inputsNode.Kind != yaml.MappingNode {
+			return &workflowDispatch
+		}
+
+		i := 0
+		for {
+			if i+1 >= len(inputsNode.Content) {
+				break
+			}
+			var input WorkflowDispatchInput
+			if decodeNode(*inputsNode.Content[i+1], &input) {
+				input.Name = inputsNode.Content[i].Value
+				workflowDispatch.Inputs = append(workflowDispatch.Inputs, input)
+			}
+			i += 2
+		}
+		return &workflowDispatch
+
+	default:
+		return nil
+	}
+	return nil
+}
+
+func decodeNode(node yaml.Node, out any) bool {
+	if err := node.Decode(out); err != nil {
+		log.Warn("Failed to decode node %v into %T: %v", node, out, err)
+		return false
+	}
+	return true
+}
+
+// ErrInvalidDispatchInput is returned by ValidateDispatchInputs when a caller-supplied
+// value doesn't satisfy the type declared for it in `workflow_dispatch.inputs`, and when
+// the workflow does not declare a `workflow_dispatch` trigger at all (Name then holds the
+// workflow's name).
+type ErrInvalidDispatchInput struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrInvalidDispatchInput) Error() string {
+	return fmt.Sprintf("invalid value for input %q: %s", e.Name, e.Reason)
+}
+
+// IsErrInvalidDispatchInput returns true if err is an ErrInvalidDispatchInput.
+func IsErrInvalidDispatchInput(err error) bool {
+	_, ok := err.(ErrInvalidDispatchInput)
+	return ok
+}
+
+// InputRequiredErr is returned by ValidateDispatchInputs when a `required: true` input
+// has neither a submitted value nor a declared default.
+type InputRequiredErr struct {
+	Name string
+}
+
+func (e InputRequiredErr) Error() string {
+	return fmt.Sprintf("input %q is required", e.Name)
+}
+
+// IsInputRequiredErr returns true if err is an InputRequiredErr.
+func IsInputRequiredErr(err error) bool {
+	_, ok := err.(InputRequiredErr)
+	return ok
+}
+
+// inputValueGetter type-checks and normalizes the raw string value submitted for a
+// `workflow_dispatch` input, returning the value that should actually be handed to the
+// dispatched run.
+type inputValueGetter func(ctx context.Context, repo *repo_model.Repository, input WorkflowDispatchInput, val string) (string, error)
+
+var inputValueGetters = map[string]inputValueGetter{
+	"boolean":     getBooleanInputValue,
+	"number":      getNumberInputValue,
+	"choice":      getChoiceInputValue,
+	"environment": getEnvironmentInputValue,
+}
+
+func getBooleanInputValue(_ context.Context, _ *repo_model.Repository, input WorkflowDispatchInput, val string) (string, error) {
+	switch val {
+	case "true", "1":
+		return "true", nil
+	case "false", "0":
+		return "false", nil
+	default:
+		return "", ErrInvalidDispatchInput{Name: input.Name, Reason: fmt.Sprintf("%q is not a boolean", val)}
+	}
+}
+
+func getNumberInputValue(_ context.Context, _ *repo_model.Repository, input WorkflowDispatchInput, val string) (string, error) {
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", ErrInvalidDispatchInput{Name: input.Name, Reason: fmt.Sprintf("%q is not a number", val)}
+	}
+	return val, nil
+}
+
+func getChoiceInputValue(_ context.Context, _ *repo_model.Repository, input WorkflowDispatchInput, val string) (string, error) {
+	if !slices.Contains(input.Options, val) {
+		return "", ErrInvalidDispatchInput{Name: input.Name, Reason: fmt.Sprintf("%q is not one of the declared options", val)}
+	}
+	return val, nil
+}
+
+func getEnvironmentInputValue(ctx context.Context, repo *repo_model.Repository, input WorkflowDispatchInput, val string) (string, error) {
+	names, err := actions_model.EnvironmentNames(ctx, repo.OwnerID, repo.ID)
+	if err != nil {
+		return "", fmt.Errorf("EnvironmentNames: %w", err)
+	}
+	if !slices.Contains(names, val) {
+		return "", ErrInvalidDispatchInput{Name: input.Name, Reason: fmt.Sprintf("%q is not a known environment", val)}
+	}
+	return val, nil
+}
+
+// ValidateDispatchInputs applies defaults, checks required inputs are present, and runs
+// the type-specific getter (boolean/number/choice/environment) declared for each input.
+// The returned map is what the dispatched workflow_dispatch event should carry.
+func ValidateDispatchInputs(ctx context.Context, repo *repo_model.Repository, wf *model.Workflow, raw map[string]string) (map[string]string, error) {
+	dispatch := WorkflowDispatchConfig(wf)
+	if dispatch == nil {
+		return nil, ErrInvalidDispatchInput{Name: wf.Name, Reason: "workflow does not accept workflow_dispatch"}
+	}
+
+	inputs := make(map[string]string, len(dispatch.Inputs))
+	for _, input := range dispatch.Inputs {
+		val, ok := raw[input.Name]
+		if !ok || val == "" {
+			val = input.Default
+		}
+		if val == "" {
+			if input.Required {
+				return nil, InputRequiredErr{Name: input.Name}
+			}
+			inputs[input.Name] = val
+			continue
+		}
+
+		getter, ok := inputValueGetters[input.Type]
+		if !ok {
+			// "string" and any unrecognized type pass through unchanged.
+			inputs[input.Name] = val
+			continue
+		}
+		normalized, err := getter(ctx, repo, input, val)
+		if err != nil {
+			return nil, err
+		}
+		inputs[input.Name] = normalized
+	}
+	return inputs, nil
+}
+
+// DispatchWorkflow loads the workflow file named workflowID at ref, validates rawInputs
+// against its declared `workflow_dispatch.inputs`, and enqueues an ActionRun for it.
+// It is the single entry point used by both the "Run workflow" web form and the
+// corresponding REST API, so the two stay in lockstep.
+func DispatchWorkflow(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, workflowID, ref string, rawInputs map[string]string) error {
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return util.NewNotExistErrorf("ref %q not found", ref)
+		}
+		return fmt.Errorf("GetCommit: %w", err)
+	}
+
+	entries, err := actions.ListWorkflows(commit)
+	if err != nil {
+		return fmt.Errorf("ListWorkflows: %w", err)
+	}
+
+	var content []byte
+	found := false
+	for _, e := range entries {
+		if e.Name() != workflowID {
+			continue
+		}
+		content, err = actions.GetContentFromEntry(e)
+		if err != nil {
+			return fmt.Errorf("GetContentFromEntry: %w", err)
+		}
+		found = true
+		break
+	}
+	if !found {
+		return util.NewNotExistErrorf("workflow %q not found at ref %q", workflowID, ref)
+	}
+
+	wf, err := model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("ReadWorkflow: %w", err)
+	}
+
+	inputs, err := ValidateDispatchInputs(ctx, repo, wf, rawInputs)
+	if err != nil {
+		return err
+	}
+
+	// This is what the dispatched run reads as `github.event.inputs`, so it has to carry
+	// the validated inputs, not just the raw, unvalidated ones the caller submitted.
+	eventPayload, err := json.Marshal(map[string]any{
+		"inputs":   inputs,
+		"ref":      ref,
+		"workflow": workflowID,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal workflow_dispatch event payload: %w", err)
+	}
+
+	run := &actions_model.ActionRun{
+		Title:         wf.Name,
+		RepoID:        repo.ID,
+		OwnerID:       repo.OwnerID,
+		WorkflowID:    workflowID,
+		TriggerUserID: doer.ID,
+		Ref:           ref,
+		CommitSHA:     commit.ID.String(),
+		Event:         "workflow_dispatch",
+		EventPayload:  string(eventPayload),
+		TriggerEvent:  "workflow_dispatch",
+		Status:        actions_model.StatusWaiting,
+	}
+
+	if err := actions_model.InsertRun(ctx, run, nil); err != nil {
+		return fmt.Errorf("InsertRun: %w", err)
+	}
+
+	log.Info("Workflow %q dispatched on %s@%s by %s", workflowID, repo.FullName(), ref, doer.Name)
+	notifyWorkflowDispatched(ctx, repo, doer, wf, workflowID, ref, inputs)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	audit_service "code.gitea.io/gitea/services/audit"
+	"code.gitea.io/gitea/services/convert"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+// redactSecretInputs masks the values of any input declared with `type: secret` before
+// they are written anywhere outside of the dispatched run itself (webhooks, audit log).
+func redactSecretInputs(wf *model.Workflow, inputs map[string]string) map[string]string {
+	dispatch := WorkflowDispatchConfig(wf)
+	if dispatch == nil {
+		return inputs
+	}
+
+	redacted := make(map[string]string, len(inputs))
+	for k, v := range inputs {
+		redacted[k] = v
+	}
+	for _, input := range dispatch.Inputs {
+		if input.Type == "secret" {
+			if _, ok := redacted[input.Name]; ok {
+				redacted[input.Name] = "***"
+			}
+		}
+	}
+	return redacted
+}
+
+// notifyWorkflowDispatched fires the workflow_dispatch webhook event and writes the
+// corresponding audit-log entry. It is best-effort: a failure here must not roll back
+// the already-enqueued ActionRun.
+func notifyWorkflowDispatched(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, wf *model.Workflow, workflowID, ref string, inputs map[string]string) {
+	redacted := redactSecretInputs(wf, inputs)
+
+	payload := &api.WorkflowDispatchPayload{
+		Workflow:   workflowID,
+		Ref:        ref,
+		Inputs:     redacted,
+		Sender:     convert.ToUser(ctx, doer, doer),
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeNone}),
+	}
+
+	if err := webhook_service.PrepareWebhooks(ctx, webhook_service.EventSource{Repository: repo}, webhook_module.HookEventWorkflowDispatch, payload); err != nil {
+		log.Error("PrepareWebhooks for workflow_dispatch on %s: %v", repo.FullName(), err)
+	}
+
+	audit_service.Record(ctx, audit_service.ActionsWorkflowDispatched, doer, repo, map[string]any{
+		"workflow": workflowID,
+		"ref":      ref,
+		"inputs":   redacted,
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecretInputs(t *testing.T) {
+	wf := mustParseWorkflow(t, `on:
+  workflow_dispatch:
+    inputs:
+      token:
+        required: true
+        type: secret
+      message:
+        required: false
+        type: string
+jobs: {}
+`)
+
+	redacted := redactSecretInputs(wf, map[string]string{
+		"token":   "super-secret",
+		"message": "hello",
+	})
+
+	assert.Equal(t, "***", redacted["token"])
+	assert.Equal(t, "hello", redacted["message"])
+}
+
+func TestRedactSecretInputs_NoDispatchConfig(t *testing.T) {
+	wf := mustParseWorkflow(t, "on: push\njobs: {}\n")
+	inputs := map[string]string{"foo": "bar"}
+	assert.Equal(t, inputs, redactSecretInputs(wf, inputs))
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseWorkflow(t *testing.T, content string) *model.Workflow {
+	t.Helper()
+	wf, err := model.ReadWorkflow(strings.NewReader(content))
+	assert.NoError(t, err)
+	return wf
+}
+
+func TestWorkflowDispatchConfig(t *testing.T) {
+	t.Run("no workflow_dispatch trigger", func(t *testing.T) {
+		wf := mustParseWorkflow(t, "on: push\njobs: {}\n")
+		assert.Nil(t, WorkflowDispatchConfig(wf))
+	})
+
+	t.Run("scalar trigger", func(t *testing.T) {
+		wf := mustParseWorkflow(t, "on: workflow_dispatch\njobs: {}\n")
+		dispatch := WorkflowDispatchConfig(wf)
+		assert.NotNil(t, dispatch)
+		assert.Empty(t, dispatch.Inputs)
+	})
+
+	t.Run("sequence trigger", func(t *testing.T) {
+		wf := mustParseWorkflow(t, "on: [push, workflow_dispatch]\njobs: {}\n")
+		assert.NotNil(t, WorkflowDispatchConfig(wf))
+	})
+
+	t.Run("mapping trigger with inputs", func(t *testing.T) {
+		wf := mustParseWorkflow(t, `on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        description: target environment
+        required: true
+        type: environment
+      dry_run:
+        required: false
+        default: "false"
+        type: boolean
+jobs: {}
+`)
+		dispatch := WorkflowDispatchConfig(wf)
+		assert.NotNil(t, dispatch)
+		assert.Len(t, dispatch.Inputs, 2)
+
+		var envInput, dryRunInput *WorkflowDispatchInput
+		for i := range dispatch.Inputs {
+			switch dispatch.Inputs[i].Name {
+			case "environment":
+				envInput = &dispatch.Inputs[i]
+			case "dry_run":
+				dryRunInput = &dispatch.Inputs[i]
+			}
+		}
+		if assert.NotNil(t, envInput) {
+			assert.True(t, envInput.Required)
+			assert.Equal(t, "environment", envInput.Type)
+		}
+		if assert.NotNil(t, dryRunInput) {
+			assert.False(t, dryRunInput.Required)
+			assert.Equal(t, "false", dryRunInput.Default)
+		}
+	})
+}
+
+func TestValidateDispatchInputs(t *testing.T) {
+	wf := mustParseWorkflow(t, `on:
+  workflow_dispatch:
+    inputs:
+      confirm:
+        required: true
+        type: boolean
+      replicas:
+        required: false
+        default: "1"
+        type: number
+      tier:
+        required: false
+        default: small
+        type: choice
+        options: ["small", "large"]
+jobs: {}
+`)
+
+	t.Run("valid inputs pass through normalized", func(t *testing.T) {
+		inputs, err := ValidateDispatchInputs(context.Background(), nil, wf, map[string]string{
+			"confirm":  "1",
+			"replicas": "3",
+			"tier":     "large",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"confirm": "true", "replicas": "3", "tier": "large"}, inputs)
+	})
+
+	t.Run("missing required input", func(t *testing.T) {
+		_, err := ValidateDispatchInputs(context.Background(), nil, wf, map[string]string{})
+		assert.True(t, IsInputRequiredErr(err))
+	})
+
+	t.Run("invalid boolean", func(t *testing.T) {
+		_, err := ValidateDispatchInputs(context.Background(), nil, wf, map[string]string{"confirm": "yes"})
+		assert.True(t, IsErrInvalidDispatchInput(err))
+	})
+
+	t.Run("invalid choice", func(t *testing.T) {
+		_, err := ValidateDispatchInputs(context.Background(), nil, wf, map[string]string{"confirm": "true", "tier": "medium"})
+		assert.True(t, IsErrInvalidDispatchInput(err))
+	})
+
+	t.Run("defaults are used when omitted", func(t *testing.T) {
+		inputs, err := ValidateDispatchInputs(context.Background(), nil, wf, map[string]string{"confirm": "true"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", inputs["replicas"])
+		assert.Equal(t, "small", inputs["tier"])
+	})
+}
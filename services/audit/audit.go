@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Action identifies the kind of event an audit-log entry records, e.g. "actions.workflow_dispatched".
+type Action string
+
+// ActionsWorkflowDispatched is recorded whenever a user triggers a workflow_dispatch run,
+// from either the web UI or the REST API.
+const ActionsWorkflowDispatched Action = "actions.workflow_dispatched"
+
+// Record persists a single audit-log entry for action, performed by doer against repo, with
+// the given structured data attached. It is best-effort: a failure to persist is logged but
+// must not roll back whatever the caller already did, so Record has no error return.
+func Record(ctx context.Context, action Action, doer *user_model.User, repo *repo_model.Repository, data map[string]any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Error("audit: marshal data for %s doer=%s repo=%s: %v", action, doer.Name, repo.FullName(), err)
+		return
+	}
+
+	if err := audit_model.CreateRecord(ctx, string(action), doer.ID, repo.ID, string(encoded)); err != nil {
+		log.Error("audit: CreateRecord for %s doer=%s repo=%s: %v", action, doer.Name, repo.FullName(), err)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ParseHookEvent reads the event-subscription fields of a webhook settings form —
+// the push_only/send_everything/choose_events radio and, when choose_events is picked,
+// the individual per-event checkboxes — into the HookEvent a create/edit POST just asked
+// to save.
+func ParseHookEvent(ctx *context.Context) *webhook_module.HookEvent {
+	return &webhook_module.HookEvent{
+		PushOnly:       ctx.FormString("events") == "push_only",
+		SendEverything: ctx.FormString("events") == "send_everything",
+		ChooseEvents:   ctx.FormString("events") == "choose_events",
+		HookEvents: webhook_module.HookEvents{
+			Create:           ctx.FormBool("create"),
+			Delete:           ctx.FormBool("delete"),
+			Push:             ctx.FormBool("push"),
+			PullRequest:      ctx.FormBool("pull_request"),
+			Repository:       ctx.FormBool("repository"),
+			Release:          ctx.FormBool("release"),
+			WorkflowDispatch: ctx.FormBool("workflow_dispatch"),
+		},
+	}
+}